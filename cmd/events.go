@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"wstolk/gmc/internal/git"
+)
+
+// outputFormat selects how maintenance steps are reported: "text" prints
+// the usual colored prose, "json" collects one event per step and prints
+// them as a single array at the end, "ndjson" prints one JSON object per
+// line as each step completes - both machine-readable modes are meant to
+// drive gmc from CI pipelines and dashboards.
+var outputFormat string
+
+// stepEvent is the structured record emitted for a maintenance step when
+// --output is json or ndjson.
+type stepEvent struct {
+	Step       string        `json:"step"`
+	Repo       string        `json:"repo"`
+	Status     string        `json:"status"`
+	DurationMs int64         `json:"duration_ms"`
+	Error      *git.GitError `json:"error,omitempty"`
+}
+
+// jsonEvents accumulates events for --output json.
+var jsonEvents []stepEvent
+
+func isStructuredOutput() bool {
+	return outputFormat == "json" || outputFormat == "ndjson"
+}
+
+// recordStep emits a structured event for a maintenance step and returns
+// err unchanged, so callers can wrap a step inline:
+// err := recordStep("fetch", repo, start, repo.FetchAndPrune(...)).
+func recordStep(step, repoPath string, start time.Time, err error) error {
+	if !isStructuredOutput() {
+		return err
+	}
+
+	event := stepEvent{
+		Step:       step,
+		Repo:       repoPath,
+		Status:     "ok",
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		event.Status = "failed"
+		if gitErr, ok := err.(*git.GitError); ok {
+			event.Error = gitErr
+		} else {
+			event.Error = &git.GitError{Op: step, RepoPath: repoPath, Err: err}
+		}
+	}
+
+	if outputFormat == "ndjson" {
+		if data, marshalErr := json.Marshal(event); marshalErr == nil {
+			fmt.Println(string(data))
+		}
+	} else {
+		jsonEvents = append(jsonEvents, event)
+	}
+
+	return err
+}
+
+// flushJSONEvents prints every event recorded so far as a single JSON
+// array. It is a no-op unless --output is json.
+func flushJSONEvents() {
+	if outputFormat != "json" {
+		return
+	}
+	if data, err := json.MarshalIndent(jsonEvents, "", "  "); err == nil {
+		fmt.Println(string(data))
+	}
+}
@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
+	"wstolk/gmc/internal/auth"
 	"wstolk/gmc/internal/git"
+	"wstolk/gmc/internal/tracker"
 	"wstolk/gmc/internal/ui"
 )
 
@@ -18,22 +21,74 @@ var rootCmd = &cobra.Command{
 2. Pull all remote branches
 3. Cleanup stale local branches that no longer exist remotely
 
-This tool helps keep your local Git repositories clean and up-to-date.`,
+Deleted branches are backed up before removal and can be recovered with
+"gmc undo", so this tool helps keep your local Git repositories clean and
+up-to-date without being a one-way door.`,
 	RunE: runMaintenance,
 }
 
 var (
-	dryRun  bool
-	verbose bool
-	remote  string
-	force   bool
+	dryRun     bool
+	verbose    bool
+	remote     string
+	force      bool
+	staleAfter string
+	protect    []string
+	authFlag   string
 )
 
 func init() {
-	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
+	// dry-run/remote/force/stale-after/protect are persistent so "gmc scan"
+	// shares the same safety gates and stale-branch configuration as the
+	// root command instead of silently deleting with none of them applied.
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
-	rootCmd.Flags().StringVar(&remote, "remote", "origin", "Remote name to use (default: origin)")
-	rootCmd.Flags().BoolVar(&force, "force", false, "Force deletion of branches with uncommitted changes")
+	rootCmd.PersistentFlags().StringVar(&remote, "remote", "origin", "Remote name to use (default: origin)")
+	rootCmd.PersistentFlags().BoolVar(&force, "force", false, "Actually delete stale branches (without it, stale branches are only listed)")
+	rootCmd.PersistentFlags().StringVar(&staleAfter, "stale-after", "", "Also flag branches whose tip is older than this as stale (e.g. 90d)")
+	rootCmd.PersistentFlags().StringSliceVar(&protect, "protect", nil, "Glob pattern(s) of branch names to never delete, e.g. release/*")
+	rootCmd.PersistentFlags().StringVar(&authFlag, "auth", "auto", "Credential source for fetching: auto, netrc, helper, ssh-agent, or none")
+	rootCmd.Flags().StringVar(&outputFormat, "output", "text", "Output format: text, json, or ndjson")
+}
+
+// currentAuthMethod validates and returns the --auth flag as an auth.Method.
+func currentAuthMethod() (auth.Method, error) {
+	switch auth.Method(authFlag) {
+	case auth.Auto, auth.Netrc, auth.Helper, auth.SSHAgent, auth.None:
+		return auth.Method(authFlag), nil
+	default:
+		return "", fmt.Errorf("invalid --auth value %q: must be one of auto, netrc, helper, ssh-agent, none", authFlag)
+	}
+}
+
+// currentStaleOptions builds a git.StaleOptions from the --stale-after and
+// --protect flags shared by the root command and "gmc scan", additionally
+// wiring up an issue tracker from ~/.config/gmc/config.yaml if one is
+// configured.
+func currentStaleOptions() (git.StaleOptions, error) {
+	opts := git.StaleOptions{Protect: protect}
+	if staleAfter != "" {
+		d, err := parseDurationWithDays(staleAfter)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --stale-after value %q: %w", staleAfter, err)
+		}
+		opts.StaleAfter = d
+	}
+
+	cfg, err := tracker.LoadConfig()
+	if err != nil {
+		return opts, fmt.Errorf("failed to load tracker config: %w", err)
+	}
+	if cfg != nil {
+		t, err := tracker.New(*cfg)
+		if err != nil {
+			return opts, fmt.Errorf("failed to configure issue tracker: %w", err)
+		}
+		opts.Tracker = t
+		opts.BranchIDPattern = cfg.BranchRegex
+	}
+
+	return opts, nil
 }
 
 func runMaintenance(cmd *cobra.Command, args []string) error {
@@ -49,83 +104,132 @@ func runMaintenance(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not a Git repository")
 	}
 
-	ui.PrintInfo("Starting Git maintenance in: %s", cwd)
+	defer flushJSONEvents()
+	structured := isStructuredOutput()
+
+	if !structured {
+		ui.PrintInfo("Starting Git maintenance in: %s", cwd)
+	}
 
 	// Open repository
 	repo, err := git.OpenRepository(cwd)
 	if err != nil {
-		ui.PrintError("Failed to open repository: %v", err)
+		if !structured {
+			ui.PrintError("Failed to open repository: %v", err)
+		}
 		return err
 	}
 
 	// Step 1: Checkout main branch
-	ui.PrintInfo("Checking out main branch...")
-	if verbose {
-		fmt.Println("  Looking for main or master branch...")
+	if !structured {
+		ui.PrintInfo("Checking out main branch...")
+		if verbose {
+			fmt.Println("  Looking for main or master branch...")
+		}
 	}
-	if err := repo.CheckoutMainBranch(); err != nil {
-		ui.PrintError("Failed to checkout main branch: %v", err)
+	start := time.Now()
+	if err := recordStep("checkout", cwd, start, repo.CheckoutMainBranch()); err != nil {
+		if !structured {
+			ui.PrintError("Failed to checkout main branch: %v", err)
+		}
 		return err
 	}
-	ui.PrintSuccess("Checked out main branch")
+	if !structured {
+		ui.PrintSuccess("Checked out main branch")
+	}
 
 	// Step 2: Fetch and prune (skip if no remote)
-	ui.PrintInfo("Fetching from remote '%s' with pruning...", remote)
-	if verbose {
-		fmt.Println("  This will update local remote-tracking branches...")
-	}
-	if err := repo.FetchAndPrune(remote); err != nil {
-		ui.PrintWarning("Skipping fetch/prune: %v", err)
+	if !structured {
+		ui.PrintInfo("Fetching from remote '%s' with pruning...", remote)
 		if verbose {
-			fmt.Println("  No remote repository found, proceeding with local cleanup only...")
+			fmt.Println("  This will update local remote-tracking branches...")
 		}
-	} else {
+	}
+	authMethod, err := currentAuthMethod()
+	if err != nil {
+		return err
+	}
+	start = time.Now()
+	if fetchErr := recordStep("fetch", cwd, start, repo.FetchAndPrune(remote, authMethod)); fetchErr != nil {
+		if !structured {
+			ui.PrintWarning("Skipping fetch/prune: %v", fetchErr)
+			if verbose {
+				fmt.Println("  No remote repository found, proceeding with local cleanup only...")
+			}
+		}
+	} else if !structured {
 		ui.PrintSuccess("Fetched and pruned remote branches")
 	}
 
 	// Step 3: Identify stale branches
-	ui.PrintInfo("Identifying stale local branches...")
-	if verbose {
-		fmt.Println("  Comparing local branches with remote branches...")
+	if !structured {
+		ui.PrintInfo("Identifying stale local branches...")
+		if verbose {
+			fmt.Println("  Comparing local branches with remote branches, trunk, and age...")
+		}
 	}
-	staleBranches, err := repo.GetStaleBranches(remote)
+	staleOpts, err := currentStaleOptions()
 	if err != nil {
-		ui.PrintError("Failed to identify stale branches: %v", err)
 		return err
 	}
+	start = time.Now()
+	staleBranches, err := repo.GetStaleBranches(remote, staleOpts)
+	if recordErr := recordStep("identify-stale", cwd, start, err); recordErr != nil {
+		if !structured {
+			ui.PrintError("Failed to identify stale branches: %v", recordErr)
+		}
+		return recordErr
+	}
 
 	if len(staleBranches) == 0 {
-		ui.PrintSuccess("No stale branches found")
+		if !structured {
+			ui.PrintSuccess("No stale branches found")
+		}
 		return nil
 	}
 
-	// Show what would be deleted
-	ui.PrintWarning("Found %d stale local branch(es):", len(staleBranches))
-	for _, branch := range staleBranches {
-		fmt.Printf("  - %s\n", branch)
+	names := make([]string, len(staleBranches))
+	for i, branch := range staleBranches {
+		names[i] = branch.Name
+	}
+
+	if !structured {
+		ui.PrintWarning("Found %d stale local branch(es):", len(staleBranches))
+		for _, branch := range staleBranches {
+			fmt.Printf("  - %s (%s)\n", branch.Name, branch.Reason)
+		}
 	}
 
 	// Delete branches (unless dry run)
 	if !dryRun {
-		if !force && len(staleBranches) > 0 {
-			ui.PrintWarning("Use --force to actually delete branches, or --dry-run to preview")
-			return fmt.Errorf("refusing to delete branches without --force flag")
+		if !force {
+			if !structured {
+				ui.PrintWarning("Use --force to actually delete branches, or --dry-run to preview")
+			}
+			err := fmt.Errorf("refusing to delete branches without --force flag")
+			return recordStep("delete", cwd, time.Now(), err)
 		}
 
-		ui.PrintInfo("Deleting stale branches...")
-		if verbose {
-			fmt.Printf("  Deleting %d branch(es)...\n", len(staleBranches))
+		if !structured && verbose {
+			fmt.Printf("  Deleting %d branch(es)...\n", len(names))
 		}
-		if err := repo.DeleteBranches(staleBranches); err != nil {
-			ui.PrintError("Failed to delete branches: %v", err)
+		start = time.Now()
+		if err := recordStep("delete", cwd, start, repo.DeleteBranches(names)); err != nil {
+			if !structured {
+				ui.PrintError("Failed to delete branches: %v", err)
+			}
 			return err
 		}
-		ui.PrintSuccess("Deleted %d stale branch(es)", len(staleBranches))
-	} else {
-		ui.PrintInfo("Dry run: would delete %d branch(es)", len(staleBranches))
+		if !structured {
+			ui.PrintSuccess("Deleted %d stale branch(es)", len(names))
+		}
+	} else if !structured {
+		ui.PrintInfo("Dry run: would delete %d branch(es)", len(names))
 	}
 
-	ui.PrintSuccess("Git maintenance completed successfully!")
+	if !structured {
+		ui.PrintSuccess("Git maintenance completed successfully!")
+	}
 	return nil
 }
 
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wstolk/gmc/internal/git"
+	"wstolk/gmc/internal/ui"
+)
+
+var (
+	scanReposFile string
+	scanWorkers   int
+	scanTimeout   time.Duration
+	scanOutput    string
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [root]",
+	Short: "Run maintenance across every Git repository in a workspace",
+	Long: `Scan walks a directory tree (or reads a list of paths from
+--repos-file) to discover every Git repository - including bare repos
+and repos nested inside a workspace - and runs the same maintenance
+pipeline used by the root command against each one concurrently with a
+worker pool. A summary report is printed once every repo has finished.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScan,
+}
+
+func init() {
+	scanCmd.Flags().StringVar(&scanReposFile, "repos-file", "", "File with one repo path per line, instead of walking root")
+	scanCmd.Flags().IntVar(&scanWorkers, "workers", 4, "Number of repositories to process concurrently")
+	scanCmd.Flags().DurationVar(&scanTimeout, "timeout", 2*time.Minute, "Per-repository timeout")
+	scanCmd.Flags().StringVar(&scanOutput, "output", "table", "Output format: table or json")
+	rootCmd.AddCommand(scanCmd)
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	staleOpts, err := currentStaleOptions()
+	if err != nil {
+		return err
+	}
+	authMethod, err := currentAuthMethod()
+	if err != nil {
+		return err
+	}
+	maintOpts := git.MaintenanceOptions{Remote: remote, DryRun: dryRun, Force: force, Stale: staleOpts, AuthMethod: authMethod}
+
+	var repoPaths []string
+
+	if scanReposFile != "" {
+		repoPaths, err = readRepoList(scanReposFile)
+	} else {
+		root := "."
+		if len(args) == 1 {
+			root = args[0]
+		}
+		repoPaths, err = git.DiscoverRepositories(root)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to collect repositories: %w", err)
+	}
+
+	if len(repoPaths) == 0 {
+		ui.PrintWarning("No Git repositories found")
+		return nil
+	}
+
+	ui.PrintInfo("Running maintenance on %d repositories with %d worker(s)...", len(repoPaths), scanWorkers)
+	reports := scanAll(repoPaths, maintOpts)
+
+	if scanOutput == "json" {
+		return json.NewEncoder(os.Stdout).Encode(reports)
+	}
+
+	ui.PrintMaintenanceReports(reports)
+	return nil
+}
+
+// readRepoList reads repository paths from path, one per line, ignoring
+// blank lines and "#" comments.
+func readRepoList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repos file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read repos file %s: %w", path, err)
+	}
+
+	return paths, nil
+}
+
+// scanAll runs the maintenance pipeline against every repo path
+// concurrently using a fixed-size worker pool, returning one report per
+// path in the same order they were given.
+func scanAll(paths []string, maintOpts git.MaintenanceOptions) []git.MaintenanceReport {
+	reports := make([]git.MaintenanceReport, len(paths))
+	jobs := make(chan int)
+
+	workers := scanWorkers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				reports[idx] = scanOne(paths[idx], maintOpts)
+			}
+		}()
+	}
+
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return reports
+}
+
+// scanOne runs the maintenance pipeline against a single repo path,
+// bounding it to scanTimeout so one unreachable remote can't stall the
+// whole sweep.
+func scanOne(path string, maintOpts git.MaintenanceOptions) git.MaintenanceReport {
+	done := make(chan git.MaintenanceReport, 1)
+
+	go func() {
+		repo, err := git.OpenRepository(path)
+		if err != nil {
+			done <- git.MaintenanceReport{Path: path, Status: "failed", Error: err.Error()}
+			return
+		}
+		done <- repo.RunMaintenance(maintOpts)
+	}()
+
+	select {
+	case report := <-done:
+		return report
+	case <-time.After(scanTimeout):
+		return git.MaintenanceReport{Path: path, Status: "failed", Error: "timed out"}
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"wstolk/gmc/internal/git"
+	"wstolk/gmc/internal/ui"
+)
+
+var undoAll bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [backup...]",
+	Short: "Restore branches deleted by a previous gmc run",
+	Long: `Undo restores one or more branches that a previous maintenance run
+deleted, from the backups kept under the refs/gmc-trash/ namespace. Pass
+one or more backup names (either "<branch>" or the fully qualified
+"<timestamp>/<branch>" if the short form is ambiguous), or --all to
+restore everything currently in the trash.`,
+	RunE: runUndo,
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoAll, "all", false, "Restore every backed-up branch")
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	if !undoAll && len(args) == 0 {
+		return fmt.Errorf("specify one or more backup names, or pass --all")
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repo, err := git.OpenRepository(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	names := args
+	if undoAll {
+		backups, err := repo.ListBackups()
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+		names = nil
+		for _, backup := range backups {
+			names = append(names, backup.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		ui.PrintInfo("No backups to restore")
+		return nil
+	}
+
+	for _, name := range names {
+		if err := repo.RestoreBackup(name); err != nil {
+			ui.PrintError("Failed to restore %s: %v", name, err)
+			return err
+		}
+		ui.PrintSuccess("Restored %s", name)
+	}
+
+	return nil
+}
+
+var gcBackupsOlderThan string
+
+var gcBackupsCmd = &cobra.Command{
+	Use:   "gc-backups",
+	Short: "Permanently remove old branch backups",
+	Long: `GC-backups prunes the refs/gmc-trash/ namespace, permanently
+removing backups older than --older-than. Run this periodically so the
+trash doesn't grow without bound.`,
+	RunE: runGCBackups,
+}
+
+func init() {
+	gcBackupsCmd.Flags().StringVar(&gcBackupsOlderThan, "older-than", "30d", "Remove backups older than this (e.g. 30d, 12h, 90m)")
+	rootCmd.AddCommand(gcBackupsCmd)
+}
+
+func runGCBackups(cmd *cobra.Command, args []string) error {
+	olderThan, err := parseDurationWithDays(gcBackupsOlderThan)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than value %q: %w", gcBackupsOlderThan, err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repo, err := git.OpenRepository(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	if err := repo.GCBackups(olderThan); err != nil {
+		return fmt.Errorf("failed to prune backups: %w", err)
+	}
+
+	ui.PrintSuccess("Pruned backups older than %s", gcBackupsOlderThan)
+	return nil
+}
+
+// parseDurationWithDays parses a duration string, additionally accepting a
+// "d" (days) suffix that time.ParseDuration doesn't support natively.
+func parseDurationWithDays(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(s, "d"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
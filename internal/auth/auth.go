@@ -0,0 +1,234 @@
+// Package auth resolves Git credentials the same way git itself does, so
+// gmc can fetch from private HTTPS/SSH remotes without shelling out to
+// `git fetch`.
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Method selects which credential source(s) Resolve may use.
+type Method string
+
+const (
+	// Auto tries every source in order and uses the first that succeeds.
+	Auto     Method = "auto"
+	Netrc    Method = "netrc"
+	Helper   Method = "helper"
+	SSHAgent Method = "ssh-agent"
+	None     Method = "none"
+)
+
+// Resolve returns the transport.AuthMethod to use when talking to
+// remoteURL. For HTTPS remotes it tries, in order, ~/.netrc and the user's
+// configured git credential helper (via `git credential fill`). For SSH
+// remotes it tries the running SSH agent and falls back to the user's
+// default key files in ~/.ssh. A nil result with a nil error means no
+// credentials were found and the caller should let go-git try anonymously.
+func Resolve(method Method, remoteURL string) (transport.AuthMethod, error) {
+	if method == None {
+		return nil, nil
+	}
+
+	if isSSHURL(remoteURL) {
+		if method != Auto && method != SSHAgent {
+			return nil, fmt.Errorf("auth method %q does not apply to SSH remote %s", method, remoteURL)
+		}
+		return sshAuth(remoteURL)
+	}
+
+	host, err := hostOf(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if method == Auto || method == Netrc {
+		if auth, ok := netrcAuth(host); ok {
+			return auth, nil
+		}
+		if method == Netrc {
+			return nil, fmt.Errorf("no .netrc entry for host %s", host)
+		}
+	}
+
+	if method == Auto || method == Helper {
+		auth, ok, helperErr := helperAuth(remoteURL)
+		if ok {
+			return auth, nil
+		}
+		if method == Helper {
+			if helperErr != nil {
+				return nil, fmt.Errorf("git credential helper failed for %s: %w", remoteURL, helperErr)
+			}
+			return nil, fmt.Errorf("git credential helper returned no credentials for %s", remoteURL)
+		}
+	}
+
+	return nil, nil
+}
+
+func isSSHURL(remoteURL string) bool {
+	if strings.HasPrefix(remoteURL, "ssh://") || strings.HasPrefix(remoteURL, "git@") {
+		return true
+	}
+	// scp-like syntax, e.g. "git@host:org/repo.git" without an explicit scheme.
+	return !strings.Contains(remoteURL, "://") && strings.Contains(remoteURL, ":")
+}
+
+func hostOf(remoteURL string) (string, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse remote URL %s: %w", remoteURL, err)
+	}
+	return u.Hostname(), nil
+}
+
+func sshUser(remoteURL string) string {
+	if strings.HasPrefix(remoteURL, "ssh://") {
+		if u, err := url.Parse(remoteURL); err == nil && u.User != nil {
+			return u.User.Username()
+		}
+	}
+	if i := strings.Index(remoteURL, "@"); i > 0 {
+		return remoteURL[:i]
+	}
+	return "git"
+}
+
+// sshAuth tries the running SSH agent first, then falls back to the user's
+// default key files, asking for a passphrase (see sshKeyPassphrase) for
+// each key file it finds, whether or not the key is encrypted.
+func sshAuth(remoteURL string) (transport.AuthMethod, error) {
+	user := sshUser(remoteURL)
+
+	if agentAuth, err := gitssh.NewSSHAgentAuth(user); err == nil {
+		return agentAuth, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("no SSH agent available and failed to resolve home directory: %w", err)
+	}
+
+	for _, name := range []string{"id_ed25519", "id_rsa", "id_ecdsa"} {
+		keyPath := filepath.Join(home, ".ssh", name)
+		if _, err := os.Stat(keyPath); err != nil {
+			continue
+		}
+		keyAuth, err := gitssh.NewPublicKeysFromFile(user, keyPath, sshKeyPassphrase(keyPath))
+		if err != nil {
+			continue
+		}
+		return keyAuth, nil
+	}
+
+	return nil, fmt.Errorf("no SSH agent and no usable key in ~/.ssh for %s", remoteURL)
+}
+
+// sshKeyPassphrase returns the passphrase to use for keyPath, reading it
+// from GMC_SSH_KEY_PASSPHRASE if set, otherwise prompting on stdin.
+func sshKeyPassphrase(keyPath string) string {
+	if pass := os.Getenv("GMC_SSH_KEY_PASSPHRASE"); pass != "" {
+		return pass
+	}
+
+	fmt.Printf("Enter passphrase for %s (leave blank if none): ", keyPath)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// netrcAuth looks up host in the user's ~/.netrc file.
+func netrcAuth(host string) (transport.AuthMethod, bool) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".netrc"))
+	if err != nil {
+		return nil, false
+	}
+
+	var machine, login, password string
+	matched := false
+	tokens := strings.Fields(string(data))
+	for i := 0; i < len(tokens); i++ {
+		if i+1 >= len(tokens) {
+			break
+		}
+		switch tokens[i] {
+		case "machine":
+			// The matched entry ends as soon as the next "machine" token
+			// starts, so capture it here before matched/login get reset.
+			if matched && login != "" {
+				return &http.BasicAuth{Username: login, Password: password}, true
+			}
+			machine = tokens[i+1]
+			matched = machine == host
+			login, password = "", ""
+		case "login":
+			if matched {
+				login = tokens[i+1]
+			}
+		case "password":
+			if matched {
+				password = tokens[i+1]
+			}
+		}
+	}
+
+	if !matched || login == "" {
+		return nil, false
+	}
+	return &http.BasicAuth{Username: login, Password: password}, true
+}
+
+// helperAuth asks the user's configured git credential helper for
+// credentials via `git credential fill`. The returned bool is false both
+// when the helper ran but had no credentials and when it failed to run at
+// all. err is set only when git exited non-zero and wrote to stderr, and
+// then carries that stderr so a caller using --auth=helper explicitly can
+// see why; other failures return a nil err.
+func helperAuth(remoteURL string) (transport.AuthMethod, bool, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("protocol=%s\nhost=%s\n\n", u.Scheme, u.Host))
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, false, fmt.Errorf("git credential fill: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, false, nil
+	}
+
+	var username, password string
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if v, ok := strings.CutPrefix(line, "username="); ok {
+			username = v
+		}
+		if v, ok := strings.CutPrefix(line, "password="); ok {
+			password = v
+		}
+	}
+
+	if username == "" {
+		return nil, false, nil
+	}
+	return &http.BasicAuth{Username: username, Password: password}, true, nil
+}
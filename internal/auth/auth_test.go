@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func TestNetrcAuth_MultipleEntries(t *testing.T) {
+	netrc := `machine github.com
+login github-user
+password github-token
+
+machine gitlab.com
+login gitlab-user
+password gitlab-token
+`
+	home := t.TempDir()
+	if err := os.WriteFile(filepath.Join(home, ".netrc"), []byte(netrc), 0600); err != nil {
+		t.Fatalf("Failed to write .netrc: %v", err)
+	}
+	t.Setenv("HOME", home)
+
+	auth, ok := netrcAuth("github.com")
+	if !ok {
+		t.Fatal("Expected a match for github.com, the non-last .netrc entry")
+	}
+	basicAuth, ok := auth.(*http.BasicAuth)
+	if !ok {
+		t.Fatalf("Expected *http.BasicAuth, got %T", auth)
+	}
+	if basicAuth.Username != "github-user" || basicAuth.Password != "github-token" {
+		t.Errorf("Got %+v, want github-user/github-token", basicAuth)
+	}
+
+	auth, ok = netrcAuth("gitlab.com")
+	if !ok {
+		t.Fatal("Expected a match for gitlab.com, the last .netrc entry")
+	}
+	basicAuth = auth.(*http.BasicAuth)
+	if basicAuth.Username != "gitlab-user" || basicAuth.Password != "gitlab-token" {
+		t.Errorf("Got %+v, want gitlab-user/gitlab-token", basicAuth)
+	}
+
+	if _, ok := netrcAuth("bitbucket.org"); ok {
+		t.Error("Expected no match for a host not in .netrc")
+	}
+}
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// backupRefPrefix is the namespace under which deleted branch tips are
+// preserved so they can be restored later.
+const backupRefPrefix = "refs/gmc-trash/"
+
+// Backup records everything needed to restore a branch that DeleteBranches
+// removed: its original tip, its upstream (if any), and when it was
+// deleted.
+type Backup struct {
+	Name      string    `json:"name"` // <timestamp>/<branch>, also the ref suffix under backupRefPrefix
+	Branch    string    `json:"branch"`
+	SHA       string    `json:"sha"`
+	Upstream  string    `json:"upstream,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// gmcDir returns the repository's private gmc state directory, creating it
+// if necessary.
+func (r *Repository) gmcDir() (string, error) {
+	dir := filepath.Join(r.path, ".git", "gmc", "trash")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create gmc state directory: %w", err)
+	}
+	return dir, nil
+}
+
+// backupBranch writes a backup ref and sidecar metadata file for branch
+// before it is deleted, so it can be found and restored later via
+// ListBackups/RestoreBackup.
+func (r *Repository) backupBranch(branch string) (Backup, error) {
+	ref, err := r.repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return Backup{}, fmt.Errorf("failed to resolve branch %s: %w", branch, err)
+	}
+
+	backup := Backup{
+		Name:      fmt.Sprintf("%s/%s", time.Now().UTC().Format("20060102T150405Z"), branch),
+		Branch:    branch,
+		SHA:       ref.Hash().String(),
+		Upstream:  r.branchUpstream(branch),
+		DeletedAt: time.Now().UTC(),
+	}
+
+	backupRef := plumbing.NewHashReference(plumbing.ReferenceName(backupRefPrefix+backup.Name), ref.Hash())
+	if err := r.repo.Storer.SetReference(backupRef); err != nil {
+		return Backup{}, fmt.Errorf("failed to write backup ref for %s: %w", branch, err)
+	}
+
+	if err := r.writeBackupMetadata(backup); err != nil {
+		return Backup{}, err
+	}
+
+	return backup, nil
+}
+
+func (r *Repository) branchUpstream(branch string) string {
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return ""
+	}
+	section := cfg.Raw.Section("branch").Subsection(branch)
+	remoteName := section.Option("remote")
+	merge := section.Option("merge")
+	if remoteName == "" || merge == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", remoteName, strings.TrimPrefix(merge, "refs/heads/"))
+}
+
+func (r *Repository) writeBackupMetadata(backup Backup) error {
+	dir, err := r.gmcDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(backup, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup metadata for %s: %w", backup.Branch, err)
+	}
+
+	path := filepath.Join(dir, metadataFileName(backup.Name))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write backup metadata for %s: %w", backup.Branch, err)
+	}
+	return nil
+}
+
+func metadataFileName(name string) string {
+	return strings.ReplaceAll(name, "/", "__") + ".json"
+}
+
+// ListBackups returns every backup currently held in the gmc-trash
+// namespace, most recently deleted first.
+func (r *Repository) ListBackups() ([]Backup, error) {
+	const op = "list-backups"
+
+	dir, err := r.gmcDir()
+	if err != nil {
+		return nil, newGitError(r.path, op, nil, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, newGitError(r.path, op, nil, fmt.Errorf("failed to list backups: %w", err))
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, newGitError(r.path, op, nil, fmt.Errorf("failed to read backup metadata %s: %w", entry.Name(), err))
+		}
+		var backup Backup
+		if err := json.Unmarshal(data, &backup); err != nil {
+			return nil, newGitError(r.path, op, nil, fmt.Errorf("failed to parse backup metadata %s: %w", entry.Name(), err))
+		}
+		backups = append(backups, backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].DeletedAt.After(backups[j].DeletedAt)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup recreates the branch recorded in the backup named name
+// (either its full "<timestamp>/<branch>" name or just the branch name,
+// provided it is unambiguous) at its original tip, and removes the
+// backup. The branch must not already exist.
+func (r *Repository) RestoreBackup(name string) error {
+	const op = "restore-backup"
+
+	backups, err := r.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	var match *Backup
+	for i, backup := range backups {
+		if backup.Name == name || backup.Branch == name {
+			if match != nil {
+				return newGitError(r.path, op, []string{name}, fmt.Errorf("ambiguous backup name, use the full <timestamp>/<branch> form"))
+			}
+			match = &backups[i]
+		}
+	}
+	if match == nil {
+		return newGitError(r.path, op, []string{name}, fmt.Errorf("no backup found matching %q", name))
+	}
+
+	if _, err := r.repo.Reference(plumbing.NewBranchReferenceName(match.Branch), true); err == nil {
+		return newGitError(r.path, op, []string{name}, fmt.Errorf("branch %s already exists, refusing to overwrite", match.Branch))
+	}
+
+	branchRef := plumbing.NewHashReference(plumbing.NewBranchReferenceName(match.Branch), plumbing.NewHash(match.SHA))
+	if err := r.repo.Storer.SetReference(branchRef); err != nil {
+		return newGitError(r.path, op, []string{name}, fmt.Errorf("failed to restore branch %s: %w", match.Branch, err))
+	}
+
+	if err := r.removeBackup(*match); err != nil {
+		return newGitError(r.path, op, []string{name}, err)
+	}
+	return nil
+}
+
+// GCBackups permanently removes backups older than olderThan.
+func (r *Repository) GCBackups(olderThan time.Duration) error {
+	const op = "gc-backups"
+
+	backups, err := r.ListBackups()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	for _, backup := range backups {
+		if backup.DeletedAt.Before(cutoff) {
+			if err := r.removeBackup(backup); err != nil {
+				return newGitError(r.path, op, []string{olderThan.String()}, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *Repository) removeBackup(backup Backup) error {
+	if err := r.repo.Storer.RemoveReference(plumbing.ReferenceName(backupRefPrefix + backup.Name)); err != nil {
+		return fmt.Errorf("failed to remove backup ref for %s: %w", backup.Branch, err)
+	}
+
+	dir, err := r.gmcDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, metadataFileName(backup.Name))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove backup metadata for %s: %w", backup.Branch, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// TestDeleteBranchesAndRestoreBackup exercises the full delete -> undo
+// round trip: deleting a branch must actually remove its ref, and
+// RestoreBackup must be able to bring it back at the same commit.
+func TestDeleteBranchesAndRestoreBackup(t *testing.T) {
+	tempDir := t.TempDir()
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = tempDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+	}
+
+	run("init")
+	run("config", "user.name", "Test User")
+	run("config", "user.email", "test@example.com")
+
+	testFile := filepath.Join(tempDir, "test.txt")
+	if err := os.WriteFile(testFile, []byte("test content"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	run("add", "test.txt")
+	run("commit", "-m", "Initial commit")
+	run("branch", "feature")
+
+	repo, err := OpenRepository(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to open temp repository: %v", err)
+	}
+
+	ref, err := repo.repo.Reference(plumbing.NewBranchReferenceName("feature"), true)
+	if err != nil {
+		t.Fatalf("Failed to resolve feature branch: %v", err)
+	}
+	originalSHA := ref.Hash().String()
+
+	if err := repo.DeleteBranches([]string{"feature"}); err != nil {
+		t.Fatalf("DeleteBranches failed: %v", err)
+	}
+
+	if _, err := repo.repo.Reference(plumbing.NewBranchReferenceName("feature"), true); err == nil {
+		t.Fatal("feature branch ref should no longer exist after DeleteBranches")
+	}
+
+	backups, err := repo.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 1 || backups[0].Branch != "feature" {
+		t.Fatalf("Expected one backup for feature, got %+v", backups)
+	}
+
+	if err := repo.RestoreBackup("feature"); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+
+	restored, err := repo.repo.Reference(plumbing.NewBranchReferenceName("feature"), true)
+	if err != nil {
+		t.Fatalf("feature branch should exist again after RestoreBackup: %v", err)
+	}
+	if restored.Hash().String() != originalSHA {
+		t.Errorf("Restored branch points at %s, expected original %s", restored.Hash().String(), originalSHA)
+	}
+
+	backups, err = repo.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups failed: %v", err)
+	}
+	if len(backups) != 0 {
+		t.Errorf("Expected no backups remaining after restore, got %d", len(backups))
+	}
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DiscoverRepositories walks the directory tree rooted at root and returns
+// the path of every Git repository it finds, including bare repositories
+// and repositories nested arbitrarily deep inside a workspace. It does not
+// descend into a repository once found, so repositories nested inside
+// another repository (e.g. submodules) are not returned.
+func DiscoverRepositories(root string) ([]string, error) {
+	var repos []string
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		if _, statErr := os.Stat(filepath.Join(path, ".git")); statErr == nil {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+
+		if isBareRepository(path) {
+			repos = append(repos, path)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// isBareRepository reports whether path looks like a bare Git repository
+// (no working tree, so no ".git" subdirectory, but the usual top-level
+// repository layout is present directly inside path).
+func isBareRepository(path string) bool {
+	for _, entry := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(path, entry)); err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GitError is the error type returned by Repository methods, carrying
+// enough structure for a caller to programmatically distinguish failure
+// classes (e.g. "remote missing" vs "auth failed" vs "checkout conflict")
+// instead of pattern-matching an error string.
+type GitError struct {
+	Op       string   `json:"op"`
+	RepoPath string   `json:"repo_path"`
+	Args     []string `json:"args,omitempty"`
+	Err      error    `json:"-"`
+}
+
+func (e *GitError) Error() string {
+	msg := fmt.Sprintf("%s: %s", e.RepoPath, e.Op)
+	if len(e.Args) > 0 {
+		msg += fmt.Sprintf(" (%s)", strings.Join(e.Args, ", "))
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *GitError) Unwrap() error { return e.Err }
+
+// MarshalJSON flattens the wrapped Err into an "error" string field, since
+// error values don't marshal generically.
+func (e *GitError) MarshalJSON() ([]byte, error) {
+	type alias GitError
+	var errMsg string
+	if e.Err != nil {
+		errMsg = e.Err.Error()
+	}
+	return json.Marshal(struct {
+		alias
+		ErrorMsg string `json:"error"`
+	}{alias: alias(*e), ErrorMsg: errMsg})
+}
+
+// newGitError wraps err as a GitError describing the failed operation op
+// against the repository at repoPath.
+func newGitError(repoPath, op string, args []string, err error) *GitError {
+	return &GitError{Op: op, RepoPath: repoPath, Args: args, Err: err}
+}
@@ -5,13 +5,17 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"wstolk/gmc/internal/auth"
 )
 
 // CheckoutMainBranch checks out the main branch (tries main first, then master)
 func (r *Repository) CheckoutMainBranch() error {
+	const op = "checkout"
+
 	w, err := r.repo.Worktree()
 	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+		return newGitError(r.path, op, nil, fmt.Errorf("failed to get worktree: %w", err))
 	}
 
 	// Try main branch first
@@ -27,99 +31,81 @@ func (r *Repository) CheckoutMainBranch() error {
 			Branch: masterRef,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to checkout main or master branch: %w", err)
+			return newGitError(r.path, op, []string{"main", "master"}, fmt.Errorf("failed to checkout main or master branch: %w", err))
 		}
 	}
 
 	return nil
 }
 
-// FetchAndPrune fetches all remote branches and prunes stale remote references
-func (r *Repository) FetchAndPrune(remoteName string) error {
-	err := r.repo.Fetch(&git.FetchOptions{
+// FetchAndPrune fetches all remote branches and prunes stale remote
+// references, authenticating with authMethod (see internal/auth) when the
+// remote requires it.
+func (r *Repository) FetchAndPrune(remoteName string, authMethod auth.Method) error {
+	const op = "fetch"
+
+	authOpt, err := r.resolveAuth(remoteName, authMethod)
+	if err != nil {
+		return newGitError(r.path, op, []string{remoteName}, fmt.Errorf("failed to resolve credentials: %w", err))
+	}
+
+	err = r.repo.Fetch(&git.FetchOptions{
 		RemoteName: remoteName,
 		Prune:      true,
+		Auth:       authOpt,
 	})
 
 	if err != nil && err != git.NoErrAlreadyUpToDate {
-		return fmt.Errorf("failed to fetch from remote %s: %w", remoteName, err)
+		return newGitError(r.path, op, []string{remoteName}, err)
 	}
 
 	return nil
 }
 
-// GetStaleBranches returns local branches that no longer exist on the remote
-func (r *Repository) GetStaleBranches(remoteName string) ([]string, error) {
-	var staleBranches []string
-
-	// Get all branches
-	branches, err := r.repo.Branches()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get branches: %w", err)
-	}
-
-	// Get remote references
-	remotes, err := r.repo.Remotes()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get remotes: %w", err)
+// resolveAuth looks up remoteName's URL and resolves credentials for it.
+func (r *Repository) resolveAuth(remoteName string, method auth.Method) (transport.AuthMethod, error) {
+	if method == "" {
+		method = auth.Auto
 	}
-
-	var remoteRefs map[string]bool
-	for _, remote := range remotes {
-		if remote.Config().Name == remoteName {
-			refs, err := remote.List(&git.ListOptions{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to list remote references: %w", err)
-			}
-
-			remoteRefs = make(map[string]bool)
-			for _, ref := range refs {
-				if ref.Name().IsBranch() {
-					// Store branch name without refs/heads/ prefix
-					branchName := ref.Name().Short()
-					remoteRefs[branchName] = true
-				}
-			}
-			break
-		}
+	if method == auth.None {
+		return nil, nil
 	}
 
-	if remoteRefs == nil {
-		return nil, fmt.Errorf("remote %s not found", remoteName)
+	remote, err := r.repo.Remote(remoteName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote %s: %w", remoteName, err)
 	}
 
-	// Check each local branch
-	err = branches.ForEach(func(ref *plumbing.Reference) error {
-		if ref.Name().IsBranch() {
-			branchName := ref.Name().Short()
-
-			// Skip current branch (HEAD)
-			head, err := r.repo.Head()
-			if err == nil && head.Name() == ref.Name() {
-				return nil
-			}
-
-			// Check if remote branch exists
-			if _, exists := remoteRefs[branchName]; !exists {
-				staleBranches = append(staleBranches, branchName)
-			}
-		}
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to iterate branches: %w", err)
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("remote %s has no URL configured", remoteName)
 	}
 
-	return staleBranches, nil
+	return auth.Resolve(method, urls[0])
 }
 
-// DeleteBranches deletes the specified local branches
+// DeleteBranches deletes the specified local branches. Before each branch
+// is removed, its tip is preserved under the refs/gmc-trash/ namespace (see
+// backup.go) so it can be recovered with Repository.RestoreBackup.
 func (r *Repository) DeleteBranches(branches []string) error {
+	const op = "delete-branch"
+
 	for _, branch := range branches {
-		err := r.repo.DeleteBranch(branch)
-		if err != nil {
-			return fmt.Errorf("failed to delete branch %s: %w", branch, err)
+		if _, err := r.repo.Reference(plumbing.NewBranchReferenceName(branch), true); err != nil {
+			return newGitError(r.path, op, []string{branch}, fmt.Errorf("branch not found: %w", err))
+		}
+
+		if _, err := r.backupBranch(branch); err != nil {
+			return newGitError(r.path, op, []string{branch}, fmt.Errorf("failed to back up branch before deleting: %w", err))
+		}
+
+		// go-git's DeleteBranch only removes the [branch "x"] config section,
+		// it does not remove the refs/heads/x reference itself.
+		if err := r.repo.DeleteBranch(branch); err != nil && err != git.ErrBranchNotFound {
+			return newGitError(r.path, op, []string{branch}, err)
+		}
+		if err := r.repo.Storer.RemoveReference(plumbing.NewBranchReferenceName(branch)); err != nil {
+			return newGitError(r.path, op, []string{branch}, fmt.Errorf("failed to remove branch ref: %w", err))
 		}
 	}
 	return nil
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"time"
+
+	"wstolk/gmc/internal/auth"
+)
+
+// MaintenanceReport describes the outcome of running the full maintenance
+// pipeline (checkout main, fetch and prune, stale-branch cleanup) against a
+// single repository, so callers can aggregate results across many repos
+// without re-parsing log output.
+type MaintenanceReport struct {
+	Path          string        `json:"path"`
+	Status        string        `json:"status"` // "ok", "skipped", or "failed"
+	FetchError    string        `json:"fetch_error,omitempty"`
+	StaleBranches []StaleBranch `json:"stale_branches,omitempty"`
+	Deleted       []string      `json:"deleted,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	Duration      time.Duration `json:"duration_ns"`
+}
+
+// MaintenanceOptions configures a single RunMaintenance call.
+type MaintenanceOptions struct {
+	Remote     string
+	DryRun     bool
+	Force      bool
+	Stale      StaleOptions
+	AuthMethod auth.Method
+}
+
+// RunMaintenance runs the standard checkout/fetch/stale-branch pipeline
+// against the repository and returns a MaintenanceReport describing what
+// happened. Unlike the individual steps, RunMaintenance never returns an
+// error directly - failures are captured on the report so a caller sweeping
+// many repositories can keep going.
+func (r *Repository) RunMaintenance(opts MaintenanceOptions) MaintenanceReport {
+	start := time.Now()
+	report := MaintenanceReport{Path: r.path}
+
+	if err := r.CheckoutMainBranch(); err != nil {
+		report.Status = "failed"
+		report.Error = err.Error()
+		report.Duration = time.Since(start)
+		return report
+	}
+
+	// A failed fetch doesn't stop the pipeline: the merged and age
+	// stale-branch criteria don't need a remote, so we record the failure and
+	// keep going rather than skip the repo entirely, matching how the root
+	// command's runMaintenance treats a fetch failure as a warning. A remote
+	// that is configured but can't be listed still fails the run in
+	// GetStaleBranches below.
+	if err := r.FetchAndPrune(opts.Remote, opts.AuthMethod); err != nil {
+		report.FetchError = err.Error()
+	}
+
+	stale, err := r.GetStaleBranches(opts.Remote, opts.Stale)
+	if err != nil {
+		report.Status = "failed"
+		report.Error = err.Error()
+		report.Duration = time.Since(start)
+		return report
+	}
+	report.StaleBranches = stale
+
+	names := make([]string, len(stale))
+	for i, branch := range stale {
+		names[i] = branch.Name
+	}
+
+	if !opts.DryRun && !opts.Force && len(names) > 0 {
+		report.Status = "skipped"
+		report.Error = "refusing to delete branches without --force"
+		report.Duration = time.Since(start)
+		return report
+	}
+
+	if !opts.DryRun && opts.Force && len(names) > 0 {
+		if err := r.DeleteBranches(names); err != nil {
+			report.Status = "failed"
+			report.Error = err.Error()
+			report.Duration = time.Since(start)
+			return report
+		}
+		report.Deleted = names
+	}
+
+	report.Status = "ok"
+	report.Duration = time.Since(start)
+	return report
+}
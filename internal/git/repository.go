@@ -1,8 +1,6 @@
 package git
 
 import (
-	"fmt"
-
 	"github.com/go-git/go-git/v5"
 )
 
@@ -16,7 +14,7 @@ type Repository struct {
 func OpenRepository(path string) (*Repository, error) {
 	repo, err := git.PlainOpen(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open repository at %s: %w", path, err)
+		return nil, newGitError(path, "open", nil, err)
 	}
 
 	return &Repository{
@@ -122,7 +122,7 @@ func TestGetStaleBranches_NoRemote(t *testing.T) {
 	}
 
 	// Should return empty list when no remote exists
-	stale, err := repo.GetStaleBranches("origin")
+	stale, err := repo.GetStaleBranches("origin", StaleOptions{})
 	if err != nil {
 		t.Errorf("Should not error when no remote exists: %v", err)
 	}
@@ -188,7 +188,7 @@ func TestWithTempRepository(t *testing.T) {
 	}
 
 	// Test get stale branches (should be empty)
-	stale, err := repo.GetStaleBranches("origin")
+	stale, err := repo.GetStaleBranches("origin", StaleOptions{})
 	if err != nil {
 		t.Errorf("Should not error when no remote exists: %v", err)
 	}
@@ -0,0 +1,305 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"wstolk/gmc/internal/tracker"
+)
+
+// StaleReason explains why a branch was classified as stale.
+type StaleReason string
+
+const (
+	// RemoteGone means the branch's remote-tracking counterpart no longer exists.
+	RemoteGone StaleReason = "RemoteGone"
+	// MergedIntoTrunk means the branch tip is already reachable from the trunk branch.
+	MergedIntoTrunk StaleReason = "MergedIntoTrunk"
+	// StaleByAge means the branch tip is older than the configured --stale-after threshold.
+	StaleByAge StaleReason = "StaleByAge"
+	// Unreachable means the branch tip commit could not be read (e.g. a corrupt or shallow object).
+	Unreachable StaleReason = "Unreachable"
+)
+
+// StaleBranch describes a single local branch gmc considers safe to remove,
+// along with enough context to explain the decision to a user.
+type StaleBranch struct {
+	Name           string
+	Reason         StaleReason
+	LastCommitTime time.Time
+	Author         string
+	Upstream       string
+}
+
+// StaleOptions configures how GetStaleBranches classifies branches.
+type StaleOptions struct {
+	// StaleAfter, if non-zero, flags branches whose tip is older than this
+	// duration as stale even if they still exist on the remote.
+	StaleAfter time.Duration
+	// Protect lists glob patterns (matched with filepath.Match) of branch
+	// names that must never be reported as stale, e.g. "release/*".
+	Protect []string
+	// Tracker, if set, is consulted to drop otherwise-stale branches whose
+	// linked issue is still open upstream. BranchIDPattern extracts the
+	// issue ID from a branch name via a named "id" capture group.
+	Tracker         tracker.Tracker
+	BranchIDPattern string
+}
+
+// GetStaleBranches returns the local branches gmc considers safe to delete,
+// classified by why they qualify: their remote-tracking branch is gone,
+// they're already merged into the trunk branch, or they've aged past
+// --stale-after. remoteName only needs to exist for the RemoteGone
+// criterion - the merged and age criteria still run without it. Branches
+// matching --protect/.gmcignore, the trunk branch, the currently checked
+// out branch, or a branch checked out in any linked worktree are never
+// returned.
+func (r *Repository) GetStaleBranches(remoteName string, opts StaleOptions) ([]StaleBranch, error) {
+	const op = "get-stale-branches"
+
+	remoteRefs, hasRemote, err := r.remoteBranchSet(remoteName)
+	if err != nil {
+		return nil, newGitError(r.path, op, []string{remoteName}, err)
+	}
+
+	protected, err := r.protectedPatterns(opts.Protect)
+	if err != nil {
+		return nil, newGitError(r.path, op, []string{remoteName}, err)
+	}
+
+	inUse, err := r.worktreeBranches()
+	if err != nil {
+		return nil, newGitError(r.path, op, []string{remoteName}, err)
+	}
+
+	trunkName, trunkCommit, err := r.trunkBranch()
+	if err != nil {
+		// No trunk branch to compare against just disables merged-detection.
+		trunkName, trunkCommit = "", nil
+	}
+
+	branches, err := r.repo.Branches()
+	if err != nil {
+		return nil, newGitError(r.path, op, []string{remoteName}, fmt.Errorf("failed to get branches: %w", err))
+	}
+
+	head, _ := r.repo.Head()
+
+	var stale []StaleBranch
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsBranch() {
+			return nil
+		}
+		branchName := ref.Name().Short()
+
+		if head != nil && head.Name() == ref.Name() {
+			return nil
+		}
+		if branchName == trunkName {
+			return nil
+		}
+		if inUse[branchName] {
+			return nil
+		}
+		if matchesAny(branchName, protected) {
+			return nil
+		}
+
+		commit, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			stale = append(stale, StaleBranch{
+				Name:     branchName,
+				Reason:   Unreachable,
+				Upstream: r.branchUpstream(branchName),
+			})
+			return nil
+		}
+
+		entry := StaleBranch{
+			Name:           branchName,
+			LastCommitTime: commit.Committer.When,
+			Author:         commit.Author.Name,
+			Upstream:       r.branchUpstream(branchName),
+		}
+
+		switch {
+		case hasRemote && !remoteRefs[branchName]:
+			entry.Reason = RemoteGone
+		case trunkCommit != nil && isMerged(commit, trunkCommit):
+			entry.Reason = MergedIntoTrunk
+		case opts.StaleAfter > 0 && time.Since(commit.Committer.When) > opts.StaleAfter:
+			entry.Reason = StaleByAge
+		default:
+			return nil
+		}
+
+		stale = append(stale, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, newGitError(r.path, op, []string{remoteName}, fmt.Errorf("failed to iterate branches: %w", err))
+	}
+
+	if opts.Tracker != nil {
+		stale, err = r.dropActiveTrackerIssues(stale, opts.Tracker, opts.BranchIDPattern)
+		if err != nil {
+			return nil, newGitError(r.path, op, []string{remoteName}, err)
+		}
+	}
+
+	return stale, nil
+}
+
+// dropActiveTrackerIssues removes branches from candidates whose linked
+// issue (as extracted from the branch name via pattern) is still open in
+// the tracker, querying every candidate issue ID in a single batch.
+func (r *Repository) dropActiveTrackerIssues(candidates []StaleBranch, t tracker.Tracker, pattern string) ([]StaleBranch, error) {
+	branchIDs := make(map[string]string, len(candidates))
+	var ids []string
+	for _, branch := range candidates {
+		if id, ok := tracker.ExtractID(pattern, branch.Name); ok {
+			branchIDs[branch.Name] = id
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return candidates, nil
+	}
+
+	active, err := t.ActiveIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue tracker: %w", err)
+	}
+
+	kept := make([]StaleBranch, 0, len(candidates))
+	for _, branch := range candidates {
+		if id, ok := branchIDs[branch.Name]; ok && active[id] {
+			continue
+		}
+		kept = append(kept, branch)
+	}
+	return kept, nil
+}
+
+func isMerged(branchTip, trunkTip *object.Commit) bool {
+	merged, err := branchTip.IsAncestor(trunkTip)
+	return err == nil && merged
+}
+
+// remoteBranchSet returns the set of branch names that exist on remoteName,
+// and whether remoteName is configured at all. A repository with no such
+// remote isn't an error here - it just means the RemoteGone criterion is
+// skipped in favor of the merged/age criteria, which don't need a remote.
+func (r *Repository) remoteBranchSet(remoteName string) (map[string]bool, bool, error) {
+	remotes, err := r.repo.Remotes()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get remotes: %w", err)
+	}
+
+	for _, remote := range remotes {
+		if remote.Config().Name != remoteName {
+			continue
+		}
+		refs, err := remote.List(&git.ListOptions{})
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to list remote references: %w", err)
+		}
+
+		branchSet := make(map[string]bool)
+		for _, ref := range refs {
+			if ref.Name().IsBranch() {
+				branchSet[ref.Name().Short()] = true
+			}
+		}
+		return branchSet, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// trunkBranch resolves the name and commit main or master points at, trying
+// main first.
+func (r *Repository) trunkBranch() (string, *object.Commit, error) {
+	for _, name := range []string{"main", "master"} {
+		ref, err := r.repo.Reference(plumbing.NewBranchReferenceName(name), true)
+		if err != nil {
+			continue
+		}
+		commit, err := r.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return "", nil, err
+		}
+		return name, commit, nil
+	}
+	return "", nil, fmt.Errorf("no main or master branch found")
+}
+
+// protectedPatterns merges the --protect globs with any patterns found in
+// a .gmcignore file at the repository root.
+func (r *Repository) protectedPatterns(extra []string) ([]string, error) {
+	patterns := append([]string{}, extra...)
+
+	f, err := os.Open(filepath.Join(r.path, ".gmcignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return patterns, nil
+		}
+		return nil, fmt.Errorf("failed to read .gmcignore: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read .gmcignore: %w", err)
+	}
+
+	return patterns, nil
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, err := filepath.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// worktreeBranches returns the set of branch names currently checked out in
+// any linked worktree. go-git has no worktree API, so this shells out to
+// git itself, mirroring how FetchAndPrune relies on go-git for everything
+// it does support.
+func (r *Repository) worktreeBranches() (map[string]bool, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = r.path
+	out, err := cmd.Output()
+	if err != nil {
+		// Older git versions or repos without worktree support: treat as none in use.
+		return map[string]bool{}, nil
+	}
+
+	branches := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "branch "); ok {
+			branches[plumbing.ReferenceName(name).Short()] = true
+		}
+	}
+	return branches, nil
+}
@@ -0,0 +1,58 @@
+package tracker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultBranchRegex matches gmc's own branch naming convention; most
+// teams will override it to match theirs.
+const defaultBranchRegex = `^(?:feature|bugfix)/(?P<id>[A-Z]+-\d+|\d+)-`
+
+// Config configures which issue tracker gmc consults before deleting stale
+// branches, and how branch names map to issue IDs.
+type Config struct {
+	Type        string `yaml:"type"` // "github", "gitlab", or "jira"
+	BaseURL     string `yaml:"base_url"`
+	Token       string `yaml:"token"`
+	Project     string `yaml:"project"` // "owner/repo" for GitHub, project ID/path for GitLab, project key for Jira
+	BranchRegex string `yaml:"branch_regex"`
+}
+
+// LoadConfig reads ~/.config/gmc/config.yaml. A missing file is not an
+// error - it returns a nil Config, meaning no tracker is configured and
+// tracker-based filtering is skipped. The token may be overridden with the
+// GMC_TRACKER_TOKEN environment variable so it never has to live in the
+// config file on disk.
+func LoadConfig() (*Config, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+
+	path := filepath.Join(home, ".config", "gmc", "config.yaml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if cfg.BranchRegex == "" {
+		cfg.BranchRegex = defaultBranchRegex
+	}
+	if token := os.Getenv("GMC_TRACKER_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,24 @@
+package tracker
+
+import "regexp"
+
+// ExtractID extracts the named "id" capture group from branchName using
+// pattern, returning false if pattern fails to compile or doesn't match.
+func ExtractID(pattern, branchName string) (string, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", false
+	}
+
+	match := re.FindStringSubmatch(branchName)
+	if match == nil {
+		return "", false
+	}
+
+	for i, name := range re.SubexpNames() {
+		if name == "id" && i < len(match) {
+			return match[i], true
+		}
+	}
+	return "", false
+}
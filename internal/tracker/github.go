@@ -0,0 +1,73 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitHubTracker checks issue state against the GitHub REST API.
+type GitHubTracker struct {
+	baseURL string
+	token   string
+	repo    string // "owner/repo"
+	client  *http.Client
+}
+
+// NewGitHubTracker builds a GitHubTracker from cfg, defaulting BaseURL to
+// the public GitHub API.
+func NewGitHubTracker(cfg Config) *GitHubTracker {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	return &GitHubTracker{baseURL: baseURL, token: cfg.Token, repo: cfg.Project, client: http.DefaultClient}
+}
+
+// ActiveIDs reports which of candidates are currently open GitHub issues
+// (or pull requests, which GitHub's issues API also serves) in t.repo.
+func (t *GitHubTracker) ActiveIDs(candidates []string) (map[string]bool, error) {
+	active := make(map[string]bool)
+
+	for _, id := range candidates {
+		url := fmt.Sprintf("%s/repos/%s/issues/%s", t.baseURL, t.repo, strings.TrimPrefix(id, "#"))
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request for issue %s: %w", id, err)
+		}
+		if t.token != "" {
+			req.Header.Set("Authorization", "Bearer "+t.token)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query issue %s: %w", id, err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %d querying issue %s", resp.StatusCode, id)
+		}
+
+		var issue struct {
+			State string `json:"state"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&issue)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode issue %s: %w", id, err)
+		}
+
+		if issue.State == "open" {
+			active[id] = true
+		}
+	}
+
+	return active, nil
+}
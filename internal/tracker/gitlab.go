@@ -0,0 +1,72 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitLabTracker checks issue state against the GitLab REST API.
+type GitLabTracker struct {
+	baseURL string
+	token   string
+	project string // URL-encoded numeric project ID or "group/project" path
+	client  *http.Client
+}
+
+// NewGitLabTracker builds a GitLabTracker from cfg, defaulting BaseURL to
+// the public GitLab instance.
+func NewGitLabTracker(cfg Config) *GitLabTracker {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	return &GitLabTracker{baseURL: baseURL, token: cfg.Token, project: url.PathEscape(cfg.Project), client: http.DefaultClient}
+}
+
+// ActiveIDs reports which of candidates are currently open ("opened")
+// GitLab issues in t.project.
+func (t *GitLabTracker) ActiveIDs(candidates []string) (map[string]bool, error) {
+	active := make(map[string]bool)
+
+	for _, id := range candidates {
+		reqURL := fmt.Sprintf("%s/api/v4/projects/%s/issues/%s", t.baseURL, t.project, id)
+		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request for issue %s: %w", id, err)
+		}
+		if t.token != "" {
+			req.Header.Set("PRIVATE-TOKEN", t.token)
+		}
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query issue %s: %w", id, err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %d querying issue %s", resp.StatusCode, id)
+		}
+
+		var issue struct {
+			State string `json:"state"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&issue)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode issue %s: %w", id, err)
+		}
+
+		if issue.State == "opened" {
+			active[id] = true
+		}
+	}
+
+	return active, nil
+}
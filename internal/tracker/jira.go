@@ -0,0 +1,71 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// JiraTracker checks issue status against the Jira REST API.
+type JiraTracker struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewJiraTracker builds a JiraTracker from cfg.
+func NewJiraTracker(cfg Config) *JiraTracker {
+	return &JiraTracker{baseURL: cfg.BaseURL, token: cfg.Token, client: http.DefaultClient}
+}
+
+// ActiveIDs reports which of candidates are Jira issues whose status
+// category is not yet "done".
+func (t *JiraTracker) ActiveIDs(candidates []string) (map[string]bool, error) {
+	active := make(map[string]bool)
+
+	for _, id := range candidates {
+		reqURL := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=status", t.baseURL, id)
+		req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request for issue %s: %w", id, err)
+		}
+		if t.token != "" {
+			req.Header.Set("Authorization", "Bearer "+t.token)
+		}
+
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query issue %s: %w", id, err)
+		}
+
+		if resp.StatusCode == http.StatusNotFound {
+			resp.Body.Close()
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status %d querying issue %s", resp.StatusCode, id)
+		}
+
+		var issue struct {
+			Fields struct {
+				Status struct {
+					StatusCategory struct {
+						Key string `json:"key"`
+					} `json:"statusCategory"`
+				} `json:"status"`
+			} `json:"fields"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&issue)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode issue %s: %w", id, err)
+		}
+
+		if issue.Fields.Status.StatusCategory.Key != "done" {
+			active[id] = true
+		}
+	}
+
+	return active, nil
+}
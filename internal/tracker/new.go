@@ -0,0 +1,17 @@
+package tracker
+
+import "fmt"
+
+// New builds the Tracker described by cfg.
+func New(cfg Config) (Tracker, error) {
+	switch cfg.Type {
+	case "github":
+		return NewGitHubTracker(cfg), nil
+	case "gitlab":
+		return NewGitLabTracker(cfg), nil
+	case "jira":
+		return NewJiraTracker(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker type %q, must be github, gitlab, or jira", cfg.Type)
+	}
+}
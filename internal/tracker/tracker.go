@@ -0,0 +1,12 @@
+// Package tracker lets gmc check an external issue tracker before deleting
+// a branch, so branches whose ticket is still open survive a maintenance
+// run even if they otherwise look stale.
+package tracker
+
+// Tracker reports which of a set of candidate issue IDs are still open
+// ("active") in the tracker. Implementations should treat an ID that no
+// longer exists (e.g. deleted or renumbered) as not active rather than
+// erroring, so a single bad ID doesn't block an entire run.
+type Tracker interface {
+	ActiveIDs(candidates []string) (map[string]bool, error)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/schollz/progressbar/v3"
+	"wstolk/gmc/internal/git"
 )
 
 // Colors for different types of output
@@ -36,6 +37,51 @@ func PrintError(format string, args ...interface{}) {
 	ErrorColor.Printf("✗ "+format+"\n", args...)
 }
 
+// PrintMaintenanceReports renders a colored summary table of maintenance
+// reports gathered from a scan, one row per repo in the order given,
+// followed by the number of successes, skips, and failures so the overall
+// health of a workspace sweep is visible at a glance. Rows are not sorted
+// or grouped by status.
+func PrintMaintenanceReports(reports []git.MaintenanceReport) {
+	var ok, skipped, failed int
+
+	for _, report := range reports {
+		switch report.Status {
+		case "ok":
+			ok++
+		case "skipped":
+			skipped++
+		default:
+			failed++
+		}
+	}
+
+	fmt.Printf("%-8s %-40s %s\n", "STATUS", "REPO", "DETAIL")
+	for _, report := range reports {
+		printColor := InfoColor
+		switch report.Status {
+		case "ok":
+			printColor = SuccessColor
+		case "skipped":
+			printColor = WarningColor
+		case "failed":
+			printColor = ErrorColor
+		}
+
+		detail := report.Error
+		if detail == "" {
+			detail = fmt.Sprintf("%d stale, %d deleted", len(report.StaleBranches), len(report.Deleted))
+			if report.FetchError != "" {
+				detail += fmt.Sprintf(" (fetch failed: %s)", report.FetchError)
+			}
+		}
+		printColor.Printf("%-8s %-40s %s\n", report.Status, report.Path, detail)
+	}
+
+	fmt.Println()
+	PrintInfo("%d ok, %d skipped, %d failed (of %d repos)", ok, skipped, failed, len(reports))
+}
+
 // CreateProgressBar creates a progress bar for operations
 func CreateProgressBar(max int, description string) *progressbar.ProgressBar {
 	return progressbar.NewOptions(max,